@@ -10,8 +10,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/landoop/tableprinter"
-
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -68,6 +66,22 @@ type Application struct {
 	commands       []*cobra.Command // commands should be builded and added on "Build" state or even after it, `AddCommand` will handle this.
 	currentCommand *cobra.Command
 
+	// outputFormats holds the formatters registered through
+	// RegisterOutputFormat, looked up by PrintObject before falling back to
+	// builtinOutputFormats.
+	outputFormats map[string]Formatter
+
+	// config is set by ApplicationBuilder.Config, nil when the app doesn't
+	// opt into the viper-backed config file / env var layer.
+	config *Config
+
+	// groups holds the sections registered through AddGroup, in
+	// registration order, used by installGroupedHelp to partition --help.
+	groups []commandGroup
+
+	// repl is set by ApplicationBuilder.EnableREPL.
+	repl bool
+
 	FriendlyErrors FriendlyErrors
 	Memory         *Memory
 
@@ -84,9 +98,10 @@ func (app *Application) Print(format string, args ...interface{}) error {
 }
 
 func (app *Application) PrintInfo(format string, args ...interface{}) error {
-	if *app.MachineFriendly || GetSilentFlag(app.currentCommand) {
-		// check both --machine-friendly and --silent(optional flag,
-		// but can be used side by side without machine friendly to disable info messages on user-friendly state)
+	if GetMachineFriendlyFlag(app.currentCommand) || GetSilentFlag(app.currentCommand) {
+		// check both --machine-friendly(or any non-table -o format) and --silent
+		// (optional flag, but can be used side by side without machine friendly
+		// to disable info messages on user-friendly state)
 		return nil
 	}
 
@@ -97,31 +112,6 @@ func (app *Application) PrintObject(v interface{}) error {
 	return PrintObject(app.currentCommand, v)
 }
 
-// func (app *Application) writeObject(out io.Writer, v interface{}, tableOnlyFilters ...interface{}) error {
-// 	machineFriendlyFlagValue := GetMachineFriendlyFlag(app.CobraCommand)
-// 	if machineFriendlyFlagValue {
-// 		prettyFlagValue := !GetJSONNoPrettyFlag(app.currentCommand)
-// 		jmesQueryPathFlagValue := GetJSONQueryFlag(app.currentCommand)
-// 		return WriteJSON(out, v, prettyFlagValue, jmesQueryPathFlagValue)
-// 	}
-//
-// 	tableprinter.Print(out, v, tableOnlyFilters...)
-// 	return nil
-// }
-
-func PrintObject(cmd *cobra.Command, v interface{}, tableOnlyFilters ...interface{}) error {
-	out := cmd.Root().OutOrStdout()
-	machineFriendlyFlagValue := GetMachineFriendlyFlag(cmd)
-	if machineFriendlyFlagValue {
-		prettyFlagValue := !GetJSONNoPrettyFlag(cmd)
-		jmesQueryPathFlagValue := GetJSONQueryFlag(cmd)
-		return WriteJSON(out, v, prettyFlagValue, jmesQueryPathFlagValue)
-	}
-
-	tableprinter.Print(out, v, tableOnlyFilters...)
-	return nil
-}
-
 func (app *Application) Write(b []byte) (int, error) {
 	if app.CobraCommand == nil {
 		return os.Stdout.Write(b)
@@ -159,11 +149,31 @@ func (app *Application) Run(output io.Writer, args []string) error {
 
 	app.commands = nil
 
-	if app.ShowSpinner && !*app.MachineFriendly {
-		return ackError(app.FriendlyErrors, ExecuteWithSpinner(rootCmd))
+	if app.repl && (len(args) == 0 || args[0] == "shell") {
+		return runREPL(app)
+	}
+
+	var execErr error
+	if app.ShowSpinner && !GetMachineFriendlyFlag(app.currentCommand) {
+		execErr = ExecuteWithSpinner(rootCmd)
+	} else {
+		execErr = rootCmd.Execute()
 	}
 
-	return ackError(app.FriendlyErrors, rootCmd.Execute())
+	// route every error through the same ErrorMapper/FriendlyError pipeline
+	// Main uses, so callers that call Run directly (instead of Main) get the
+	// same exit-code/hint/docs/JSON behavior, and Main itself never has to
+	// re-classify (and potentially re-render) what Run already handled.
+	fe := mapError(execErr)
+	if fe == nil {
+		return nil
+	}
+
+	if err := renderFriendlyError(app, fe); err != nil {
+		return err
+	}
+
+	return fe
 }
 
 func (app *Application) exampleText(str string) string {
@@ -258,6 +268,12 @@ func Build(app *Application) *cobra.Command {
 
 	if app.FriendlyErrors == nil {
 		app.FriendlyErrors = FriendlyErrors{}
+	} else if len(app.FriendlyErrors) > 0 {
+		// bridge the legacy map-based mapping into the ErrorMapper pipeline
+		// mapError consults, so it keeps working now that Run no longer
+		// calls ackError directly. Build only runs once per Application (see
+		// the early return above), so this can't double-register.
+		RegisterErrorMapper(legacyFriendlyErrorMapper(app.FriendlyErrors))
 	}
 
 	if app.Memory == nil {
@@ -287,6 +303,7 @@ func Build(app *Application) *cobra.Command {
 	app.MachineFriendly = new(bool)
 	if !app.DisableOutputFormatController {
 		RegisterMachineFriendlyFlagTo(rootCmd.PersistentFlags(), app.MachineFriendly)
+		RegisterOutputFlagTo(rootCmd.PersistentFlags(), new(string))
 	}
 
 	fs := rootCmd.PersistentFlags()
@@ -294,6 +311,10 @@ func Build(app *Application) *cobra.Command {
 		app.PersistentFlags(fs)
 	}
 
+	if app.config != nil {
+		app.config.bindPersistentFlags(fs)
+	}
+
 	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
 		app.currentCommand = cmd // bind current command here.
 
@@ -312,6 +333,8 @@ func Build(app *Application) *cobra.Command {
 		return nil
 	}
 
+	rootCmd.AddCommand(newCompletionCommand(app))
+
 	if len(app.commands) > 0 {
 		for _, cmd := range app.commands {
 			rootCmd.AddCommand(cmd)
@@ -326,6 +349,8 @@ func Build(app *Application) *cobra.Command {
 		rootCmd.Example = exampleText
 	}
 
+	installGroupedHelp(app, rootCmd)
+
 	if app.HelpTemplate != nil {
 		if helpTmpl := app.HelpTemplate.String(); helpTmpl != "" {
 			rootCmd.SetVersionTemplate(helpTmpl)
@@ -346,9 +371,9 @@ func GetMachineFriendlyFlagFrom(set *pflag.FlagSet) bool {
 	return b
 }
 
-func GetMachineFriendlyFlag(cmd *cobra.Command) bool {
-	return GetMachineFriendlyFlagFrom(cmd.Flags())
-}
+// GetMachineFriendlyFlag is defined in output.go, it now also reports true
+// for any `-o/--output` value other than "table" so `--machine-friendly`
+// keeps working as a `-o json` alias.
 
 func RegisterMachineFriendlyFlagTo(set *pflag.FlagSet, ptr *bool) {
 	if !GetMachineFriendlyFlagFrom(set) {
@@ -0,0 +1,60 @@
+package bite
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// DynamicCompletionFunc is the shape a bite command gives cobra to complete
+// its positional arguments at runtime, i.e. `topics get <TAB>` querying the
+// underlying Kafka client for the live topic list instead of a static list.
+type DynamicCompletionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// SetDynamicCompletion wires fn as cmd's ValidArgsFunction, it's the single
+// place bite commands should use instead of touching cobra's field directly
+// so the wiring stays consistent as cobra's completion API evolves.
+func SetDynamicCompletion(cmd *cobra.Command, fn DynamicCompletionFunc) {
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return fn(cmd, args, toComplete)
+	}
+}
+
+// RegisterFlagCompletionExtensions marks flagName on fs so shell completion
+// only suggests files with the given extensions, i.e.
+// RegisterFlagCompletionExtensions(fs, "config", "yaml", "yml", "json").
+func RegisterFlagCompletionExtensions(fs *Flags, flagName string, extensions ...string) error {
+	return fs.SetAnnotation(flagName, cobra.BashCompFilenameExt, extensions)
+}
+
+// newCompletionCommand builds the `completion [bash|zsh|fish|powershell]`
+// subcommand every Application gets for free, writing the generated script
+// to stdout so it can be sourced directly, i.e.
+// `source <(myapp completion bash)`.
+func newCompletionCommand(app *Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:       "completion [bash|zsh|fish|powershell]",
+		Short:     "Generate shell completion script",
+		Long:      app.exampleText("completion bash") + " generates a completion script for the given shell and prints it to stdout.",
+		ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+		Args:      cobra.ExactValidArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletion(os.Stdout)
+			case "zsh":
+				return root.GenZshCompletion(os.Stdout)
+			case "fish":
+				return root.GenFishCompletion(os.Stdout, true)
+			case "powershell":
+				return root.GenPowerShellCompletion(os.Stdout)
+			default:
+				return fmt.Errorf("completion: unsupported shell %q", args[0])
+			}
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,87 @@
+package bite
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// newTestApplication builds a minimal, throwaway Application so completion
+// generation has a real *cobra.Command tree to work against.
+func newTestApplication(t *testing.T, name string) *Application {
+	t.Helper()
+
+	app := &Application{Name: name, Version: "1.0.0"}
+	app.AddCommand(&cobra.Command{
+		Use:   "get",
+		Short: "get something",
+		Run:   func(cmd *cobra.Command, args []string) {},
+	})
+
+	Build(app)
+	return app
+}
+
+// generateCompletion renders shell into a temp file via gen and returns its
+// path, the file is removed when the test finishes.
+func generateCompletion(t *testing.T, shell string, gen func(w *bytes.Buffer) error) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := gen(&buf); err != nil {
+		t.Fatalf("generate %s completion: %v", shell, err)
+	}
+
+	f, err := os.CreateTemp(t.TempDir(), "bite-completion-*."+shell)
+	if err != nil {
+		t.Fatalf("create temp completion file: %v", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		t.Fatalf("write completion file: %v", err)
+	}
+
+	return f.Name()
+}
+
+// TestCompletionBashIsValidScript renders the generated bash completion
+// script and runs it through a real `bash -n` subshell, which parses
+// (without executing) the file, catching any syntax error in what
+// newCompletionCommand's "bash" case hands to cobra.
+func TestCompletionBashIsValidScript(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not installed")
+	}
+
+	app := newTestApplication(t, "bite-completion-test")
+	path := generateCompletion(t, "bash", func(buf *bytes.Buffer) error {
+		return app.CobraCommand.GenBashCompletion(buf)
+	})
+
+	out, err := exec.Command("bash", "-n", path).CombinedOutput()
+	if err != nil {
+		t.Fatalf("bash -n %s: %v\n%s", path, err, out)
+	}
+}
+
+// TestCompletionZshIsValidScript mirrors TestCompletionBashIsValidScript
+// for zsh, skipping when zsh isn't available on the test machine.
+func TestCompletionZshIsValidScript(t *testing.T) {
+	if _, err := exec.LookPath("zsh"); err != nil {
+		t.Skip("zsh not installed")
+	}
+
+	app := newTestApplication(t, "bite-completion-test")
+	path := generateCompletion(t, "zsh", func(buf *bytes.Buffer) error {
+		return app.CobraCommand.GenZshCompletion(buf)
+	})
+
+	out, err := exec.Command("zsh", "-n", path).CombinedOutput()
+	if err != nil {
+		t.Fatalf("zsh -n %s: %v\n%s", path, err, out)
+	}
+}
@@ -0,0 +1,182 @@
+package bite
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// Config wires viper to the Application, it's what ApplicationBuilder.Config
+// sets up and what the generated `config` subcommand group and
+// Application.ConfigGet/ConfigSet operate on.
+type Config struct {
+	v *viper.Viper
+	// name is the base filename passed to ApplicationBuilder.Config, i.e.
+	// "myapp" for "myapp.yaml", used to name the file ConfigSet creates
+	// when none was found on disk yet.
+	name string
+	// path is the config file viper ended up reading from (or will write
+	// to on `config set`/`use-context`), empty until the first successful
+	// ReadInConfig or explicit `config set`.
+	path string
+}
+
+// Config wires viper to this Application: it loads `<name>.yaml|json|toml`
+// from $XDG_CONFIG_HOME, $HOME/.<appname>, the current directory and then
+// searchPaths (searched in that order, the first path holding a matching
+// file is the one read), has Build bind every persistent flag of the root
+// command so it can be overridden by the config file or an environment
+// variable, and registers the `config` subcommand group.
+//
+// Resolution order, matching spf13 conventions, is:
+// explicit flag > environment variable > config file > default.
+func (b *ApplicationBuilder) Config(name string, searchPaths ...string) *ApplicationBuilder {
+	v := viper.New()
+	v.SetConfigName(name)
+
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		v.AddConfigPath(xdg)
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		v.AddConfigPath(filepath.Join(home, "."+b.app.Name))
+	}
+	v.AddConfigPath(".")
+	for _, p := range searchPaths {
+		v.AddConfigPath(p)
+	}
+
+	envPrefix := strings.ToUpper(strings.Replace(b.app.Name, "-", "_", -1))
+	v.SetEnvPrefix(envPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer("-", "_", ".", "_"))
+	v.AutomaticEnv()
+
+	// config files are optional, a missing one just means "use flags, env
+	// and defaults only".
+	_ = v.ReadInConfig()
+
+	cfg := &Config{v: v, name: name, path: v.ConfigFileUsed()}
+	b.app.config = cfg
+
+	b.app.AddCommand(newConfigCommand(b.app))
+
+	return b
+}
+
+// bindPersistentFlags binds every flag in fs to viper so flag > env >
+// config > default resolution falls out of viper.Get for free. It's called
+// once the root command's persistent flags are known, from Build.
+func (cfg *Config) bindPersistentFlags(fs *pflag.FlagSet) {
+	fs.VisitAll(func(f *pflag.Flag) {
+		_ = cfg.v.BindPFlag(f.Name, f)
+	})
+}
+
+// ConfigGet reads key with the same flag > env > config > default
+// resolution Config uses internally, it's the Memory-compatible accessor
+// existing app.Memory consumers can use without caring where the value
+// came from.
+func (app *Application) ConfigGet(key string) interface{} {
+	if app.config == nil {
+		return nil
+	}
+
+	return app.config.v.Get(key)
+}
+
+// ConfigSet persists key=value to the resolved config file and backs the
+// `config set` subcommand. Only key is written: the file is read fresh
+// into a viper instance of its own (never bound to any pflag) so the
+// current *default* value of every other persistent flag doesn't get
+// baked in as if the user had explicitly set it, which would corrupt the
+// flag > env > config > default resolution order for unrelated flags.
+func (app *Application) ConfigSet(key string, value interface{}) error {
+	if app.config == nil {
+		return fmt.Errorf("config: %s has no config file configured, call ApplicationBuilder.Config first", app.Name)
+	}
+
+	if app.config.path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+
+		dir := filepath.Join(home, "."+app.Name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+
+		app.config.path = filepath.Join(dir, app.config.name+".yaml")
+	}
+
+	fileConfig := viper.New()
+	fileConfig.SetConfigFile(app.config.path)
+	if err := fileConfig.ReadInConfig(); err != nil {
+		if _, notFound := err.(viper.ConfigFileNotFoundError); !notFound && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	fileConfig.Set(key, value)
+	if err := fileConfig.WriteConfigAs(app.config.path); err != nil {
+		return err
+	}
+
+	// keep the live resolver used by ConfigGet/flag binding in sync for the
+	// remainder of this process.
+	app.config.v.Set(key, value)
+	return nil
+}
+
+func newConfigCommand(app *Application) *cobra.Command {
+	root := &cobra.Command{
+		Use:   "config",
+		Short: fmt.Sprintf("Manage the %s config file", app.Name),
+	}
+
+	root.AddCommand(&cobra.Command{
+		Use:   "view",
+		Short: "Print the merged config (flags, env, file and defaults)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return PrintObject(cmd, app.config.v.AllSettings())
+		},
+	})
+
+	root.AddCommand(&cobra.Command{
+		Use:   "set key=val",
+		Short: "Set a config key and persist it to the config file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, value, ok := splitKeyValue(args[0])
+			if !ok {
+				return fmt.Errorf("config set: expected key=val, got %q", args[0])
+			}
+
+			return app.ConfigSet(key, value)
+		},
+	})
+
+	root.AddCommand(&cobra.Command{
+		Use:   "use-context name",
+		Short: "Switch the active context and persist it to the config file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.ConfigSet("context", args[0])
+		},
+	})
+
+	return root
+}
+
+func splitKeyValue(s string) (key, value string, ok bool) {
+	idx := strings.IndexByte(s, '=')
+	if idx == -1 {
+		return "", "", false
+	}
+
+	return s[:idx], s[idx+1:], true
+}
@@ -0,0 +1,133 @@
+package bite
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+// frontMatter is the Hugo-style metadata written at the top of every
+// generated markdown page, it's what lets the doc site ingest the tree
+// without extra tooling. weight increases in the order cobra/doc visits
+// commands, which is alphabetical within each level, so the site's nav
+// roughly matches `--help`'s ordering.
+func frontMatter(title string, weight int) string {
+	slug := strings.Replace(title, " ", "-", -1)
+	return fmt.Sprintf("---\ntitle: %q\nslug: %q\nweight: %d\n---\n\n", title, slug, weight)
+}
+
+// GenerateMarkdownTree builds app and writes one markdown file per
+// non-hidden command into dir (cobra/doc already skips hidden commands via
+// IsAvailableCommand), each carrying Hugo front-matter (title, slug,
+// weight) plus an appendix page with the HelpTemplate build metadata
+// (version, revision, datetime) the docs were generated from.
+func GenerateMarkdownTree(app *Application, dir string) error {
+	rootCmd := Build(app)
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	weight := 0
+	filePrepender := func(filename string) string {
+		title := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+		fm := frontMatter(strings.Replace(title, "_", " ", -1), weight)
+		weight++
+		return fm
+	}
+	linkHandler := func(name string) string { return name }
+
+	if err := doc.GenMarkdownTreeCustom(rootCmd, dir, filePrepender, linkHandler); err != nil {
+		return err
+	}
+
+	return writeAppendixPage(app, dir, "appendix.md", appendixMarkdown)
+}
+
+// GenerateManTree builds app and writes one man page per non-hidden
+// command into dir using cobra/doc's man generator.
+func GenerateManTree(app *Application, dir string) error {
+	rootCmd := Build(app)
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	header := &doc.GenManHeader{
+		Title:   strings.ToUpper(app.Name),
+		Section: "1",
+		Source:  app.Name + " " + app.Version,
+	}
+
+	return doc.GenManTree(rootCmd, header, dir)
+}
+
+// GenerateReSTTree builds app and writes one reST file per non-hidden
+// command into dir using cobra/doc's reST generator.
+func GenerateReSTTree(app *Application, dir string) error {
+	rootCmd := Build(app)
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	return doc.GenReSTTree(rootCmd, dir)
+}
+
+func appendixMarkdown(app *Application) string {
+	var b strings.Builder
+	b.WriteString("---\ntitle: \"Appendix\"\nslug: \"appendix\"\nweight: 9999\n---\n\n")
+	b.WriteString(fmt.Sprintf("Generated from %s %s on %s.\n", app.Name, app.Version, time.Now().UTC().Format(time.RFC3339)))
+
+	if h, ok := app.HelpTemplate.(HelpTemplate); ok {
+		b.WriteString(fmt.Sprintf("\n- revision: %s\n- build datetime: %s\n", h.BuildRevision, h.BuildTime))
+	}
+
+	return b.String()
+}
+
+func writeAppendixPage(app *Application, dir, filename string, render func(*Application) string) error {
+	return os.WriteFile(filepath.Join(dir, filename), []byte(render(app)), 0o644)
+}
+
+// EnableDocsCommand registers a hidden `docs` subcommand that calls
+// GenerateMarkdownTree, GenerateManTree or GenerateReSTTree at runtime,
+// controlled by its `--format` (md, man, rest) and `--out` flags. It's
+// opt-in since most downstream CLIs ship docs out of band rather than at
+// runtime.
+func (b *ApplicationBuilder) EnableDocsCommand() *ApplicationBuilder {
+	b.app.AddCommand(newDocsCommand(b.app))
+	return b
+}
+
+func newDocsCommand(app *Application) *cobra.Command {
+	var format, out string
+
+	cmd := &cobra.Command{
+		Use:    "docs",
+		Short:  "Generate reference documentation for this CLI",
+		Hidden: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch format {
+			case "md", "markdown":
+				return GenerateMarkdownTree(app, out)
+			case "man":
+				return GenerateManTree(app, out)
+			case "rest":
+				return GenerateReSTTree(app, out)
+			default:
+				return fmt.Errorf("docs: unknown --format %q, expected one of: md, man, rest", format)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "md", "--format, docs format, one of: md, man, rest")
+	cmd.Flags().StringVar(&out, "out", "./docs", "--out, output directory")
+
+	return cmd
+}
@@ -0,0 +1,172 @@
+package bite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// FriendlyError is the structured shape an ErrorMapper produces out of an
+// arbitrary error: a stable exit Code scripts can branch on, a human
+// Message, an optional Hint/Docs pointing at how to fix or read more, and
+// whether Retryable callers may want to retry the same command as-is.
+type FriendlyError struct {
+	Code      int    `json:"code"`
+	Message   string `json:"message"`
+	Hint      string `json:"hint,omitempty"`
+	Docs      string `json:"docs,omitempty"`
+	Retryable bool   `json:"retryable"`
+}
+
+func (fe *FriendlyError) Error() string {
+	return fe.Message
+}
+
+// ErrorMapper turns err into a FriendlyError, or returns nil to defer to
+// the next registered mapper (and eventually the default one).
+type ErrorMapper func(error) *FriendlyError
+
+// errorMappers are tried in registration order before defaultErrorMapper,
+// so downstream packages can override or extend the built-in mappings.
+var errorMappers []ErrorMapper
+
+// RegisterErrorMapper adds fn to the chain mapError consults, i.e. a
+// lenses-cli package mapping its own client errors to Code/Hint pairs.
+func RegisterErrorMapper(fn ErrorMapper) {
+	errorMappers = append(errorMappers, fn)
+}
+
+// legacyFriendlyErrorMapper bridges the old map-based FriendlyErrors (error
+// message substring -> friendly message) into an ErrorMapper, so existing
+// downstream CLIs that populate Application.FriendlyErrors keep getting
+// their custom messages now that Run maps every error through this
+// subsystem instead of calling ackError directly. Build registers one of
+// these per Application.
+func legacyFriendlyErrorMapper(fe FriendlyErrors) ErrorMapper {
+	return func(err error) *FriendlyError {
+		msg := err.Error()
+		for substr, friendly := range fe {
+			if substr != "" && strings.Contains(msg, substr) {
+				return &FriendlyError{Code: 1, Message: friendly}
+			}
+		}
+
+		return nil
+	}
+}
+
+// mapError resolves err to a FriendlyError, trying every registered mapper
+// before falling back to defaultErrorMapper. Returns nil for a nil err.
+func mapError(err error) *FriendlyError {
+	if err == nil {
+		return nil
+	}
+
+	var fe *FriendlyError
+	if errors.As(err, &fe) {
+		return fe
+	}
+
+	for _, fn := range errorMappers {
+		if fe := fn(err); fe != nil {
+			return fe
+		}
+	}
+
+	return defaultErrorMapper(err)
+}
+
+// defaultErrorMapper recognizes context.DeadlineExceeded, wrapped
+// *url.Error and falls back to a generic code-1 mapping otherwise. Exit
+// codes mirror sysexits.h-style conventions used elsewhere in this
+// ecosystem (124 for timeouts, 77 for auth failures).
+func defaultErrorMapper(err error) *FriendlyError {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return &FriendlyError{
+			Code:      124,
+			Message:   err.Error(),
+			Hint:      "the request took too long to complete, check connectivity to the cluster or increase the command's timeout",
+			Retryable: true,
+		}
+	case isAuthError(err):
+		return &FriendlyError{
+			Code:      77,
+			Message:   err.Error(),
+			Hint:      "check your credentials and that the configured user has access to this resource",
+			Retryable: false,
+		}
+	default:
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return &FriendlyError{
+				Code:      124,
+				Message:   err.Error(),
+				Hint:      fmt.Sprintf("could not reach %s, check the endpoint is correct and reachable", urlErr.URL),
+				Retryable: true,
+			}
+		}
+	}
+
+	return &FriendlyError{Code: 1, Message: err.Error()}
+}
+
+// isAuthError recognizes the handful of error strings Kafka clients in
+// this ecosystem return for authentication/authorization failures, there's
+// no shared sentinel error across them to use errors.Is on.
+func isAuthError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, sub := range []string{"sasl", "authentication failed", "not authorized", "unauthorized"} {
+		if strings.Contains(msg, sub) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// renderFriendlyError writes fe to app's output, as `{"error":...}` JSON
+// when the resolved `-o` format is `json` (or `--machine-friendly` is
+// set), or as a human message plus hint/docs lines otherwise. Any other
+// `-o` value (yaml, csv, go-template=...) still gets the human rendering,
+// since there's no "error" shape defined for those formats.
+func renderFriendlyError(app *Application, fe *FriendlyError) error {
+	cmd := app.currentCommand
+	if cmd == nil {
+		cmd = app.CobraCommand
+	}
+
+	if cmd != nil && IsJSONOutput(cmd) {
+		return WriteJSON(app, map[string]*FriendlyError{"error": fe}, true, "")
+	}
+
+	app.Print("%s\n", fe.Message)
+	if fe.Hint != "" {
+		app.Print("hint: %s\n", fe.Hint)
+	}
+	if fe.Docs != "" {
+		app.Print("docs: %s\n", fe.Docs)
+	}
+
+	return nil
+}
+
+// Main runs app and exits the process with the FriendlyError's Code
+// resolved from whatever error Run returned, 0 on success. It's the
+// replacement for hand-rolling `if err := app.Run(...); err != nil {
+// os.Exit(1) }` in a CLI's main package. Run already renders the error
+// (human hint/docs or `{"error":...}` JSON) through this same mapError
+// pipeline before returning it, so Main only needs the resolved exit code,
+// not a second render pass.
+func (app *Application) Main() {
+	err := app.Run(nil, nil)
+	fe := mapError(err)
+	if fe == nil {
+		os.Exit(0)
+	}
+
+	os.Exit(fe.Code)
+}
@@ -0,0 +1,100 @@
+package bite
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// commandGroupAnnotation is the cobra.Command.Annotations key AddGroup
+// stamps on every command it's given, so the custom help function below
+// knows which section a command belongs to.
+const commandGroupAnnotation = "bite_group"
+
+// commandGroup is one section of the grouped help output, i.e. kubectl's
+// "Basic Commands", "Cluster Management" and "Troubleshooting" sections.
+type commandGroup struct {
+	Name        string
+	Description string
+}
+
+// AddGroup registers cmds under a named section of the root command's help
+// output and adds them to app exactly like AddCommand would. Call it
+// instead of AddCommand for any command that should show up under its own
+// heading rather than the flat "Available Commands" list.
+func (app *Application) AddGroup(name, description string, cmds ...*cobra.Command) {
+	app.groups = append(app.groups, commandGroup{Name: name, Description: description})
+
+	for _, cmd := range cmds {
+		if cmd.Annotations == nil {
+			cmd.Annotations = make(map[string]string)
+		}
+		cmd.Annotations[commandGroupAnnotation] = name
+
+		app.AddCommand(cmd)
+	}
+}
+
+// groupOf returns the group name cmd was registered under via AddGroup, or
+// "" when it was added directly with AddCommand.
+func groupOf(cmd *cobra.Command) string {
+	return cmd.Annotations[commandGroupAnnotation]
+}
+
+// installGroupedHelp overrides rootCmd's help function to partition
+// `--help` output into app.groups' sections, followed by an "Other
+// Commands" section for anything added without AddGroup. It's a no-op
+// when no group was ever registered, so ungrouped Applications keep
+// cobra's default help untouched.
+func installGroupedHelp(app *Application, rootCmd *cobra.Command) {
+	if len(app.groups) == 0 {
+		return
+	}
+
+	defaultHelpFunc := rootCmd.HelpFunc()
+	rootCmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		if cmd != rootCmd || !cmd.HasAvailableSubCommands() {
+			defaultHelpFunc(cmd, args)
+			return
+		}
+
+		out := cmd.OutOrStdout()
+		fmt.Fprintln(out, cmd.Long)
+		fmt.Fprintf(out, "\nUsage:\n  %s\n", cmd.UseLine())
+
+		grouped := make(map[string]bool)
+		for _, g := range app.groups {
+			var names []*cobra.Command
+			for _, c := range cmd.Commands() {
+				if groupOf(c) == g.Name && c.IsAvailableCommand() {
+					names = append(names, c)
+				}
+			}
+			if len(names) == 0 {
+				continue
+			}
+
+			fmt.Fprintf(out, "\n%s:\n", g.Name)
+			for _, c := range names {
+				fmt.Fprintf(out, "  %-15s %s\n", c.Name(), c.Short)
+				grouped[c.Name()] = true
+			}
+		}
+
+		var other []*cobra.Command
+		for _, c := range cmd.Commands() {
+			if c.IsAvailableCommand() && !grouped[c.Name()] {
+				other = append(other, c)
+			}
+		}
+		if len(other) > 0 {
+			fmt.Fprintln(out, "\nOther Commands:")
+			for _, c := range other {
+				fmt.Fprintf(out, "  %-15s %s\n", c.Name(), c.Short)
+			}
+		}
+
+		fmt.Fprintf(out, "\nFlags:\n%s\n", cmd.Flags().FlagUsages())
+		fmt.Fprintf(out, "Use \"%s [command] --help\" for more information about a command.\n", cmd.CommandPath())
+	})
+}
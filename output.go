@@ -0,0 +1,462 @@
+package bite
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/ghodss/yaml"
+	"github.com/landoop/tableprinter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// FormatOptions carries the per-command rendering preferences a Formatter
+// needs, it's built once per PrintObject call out of the current
+// `--query`, `--no-pretty` and `-o/--output` flag values so a formatter
+// never has to look at the command itself.
+type FormatOptions struct {
+	// Pretty reports whether the output should be indented, honored by
+	// formatters that support it, i.e. json.
+	Pretty bool
+	// Query is the JMESPath expression given through `--query`, empty when
+	// not set.
+	Query string
+	// Template holds whatever followed the `=` in the `-o` value, i.e. the
+	// template body for `go-template=...`, the file path for
+	// `go-template-file=...` or the expression for `jsonpath=...`.
+	Template string
+}
+
+// Formatter renders v to out according to opts. Built-in formats and the
+// ones registered through Application.RegisterOutputFormat all share this
+// signature.
+type Formatter func(out io.Writer, v interface{}, opts FormatOptions) error
+
+const outputFlagKey = "output"
+const defaultOutputFormat = "table"
+
+// builtinOutputFormats are always available, regardless of what a downstream
+// CLI registers through RegisterOutputFormat.
+var builtinOutputFormats = map[string]Formatter{
+	"table":            tableFormatter,
+	"json":             jsonFormatter,
+	"yaml":             yamlFormatter,
+	"csv":              csvFormatter,
+	"name":             nameFormatter,
+	"go-template":      goTemplateFormatter,
+	"go-template-file": goTemplateFileFormatter,
+	"jsonpath":         jsonPathFormatter,
+}
+
+func tableFormatter(out io.Writer, v interface{}, _ FormatOptions) error {
+	tableprinter.Print(out, v)
+	return nil
+}
+
+func jsonFormatter(out io.Writer, v interface{}, opts FormatOptions) error {
+	return WriteJSON(out, v, opts.Pretty, opts.Query)
+}
+
+func yamlFormatter(out io.Writer, v interface{}, _ FormatOptions) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	y, err := yaml.JSONToYAML(b)
+	if err != nil {
+		return err
+	}
+
+	_, err = out.Write(y)
+	return err
+}
+
+func csvFormatter(out io.Writer, v interface{}, _ FormatOptions) error {
+	rows, err := csvRows(v)
+	if err != nil {
+		return err
+	}
+
+	w := csv.NewWriter(out)
+	if err := w.WriteAll(rows); err != nil {
+		return err
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// csvRows derives a header row plus one row per element out of v the same
+// way the table formatter does, via struct reflection, so every call site
+// that already does `PrintObject(cmd, someStructSlice)` works with `-o csv`
+// without pre-flattening to [][]string. A [][]string is still accepted
+// as-is for callers that already built their own rows.
+func csvRows(v interface{}) ([][]string, error) {
+	if rows, ok := v.([][]string); ok {
+		return rows, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		if rv.Len() == 0 {
+			return nil, nil
+		}
+
+		header, err := csvHeader(rv.Index(0))
+		if err != nil {
+			return nil, err
+		}
+
+		rows := [][]string{header}
+		for i := 0; i < rv.Len(); i++ {
+			row, err := csvRow(rv.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			rows = append(rows, row)
+		}
+
+		return rows, nil
+	case reflect.Struct:
+		header, err := csvHeader(rv)
+		if err != nil {
+			return nil, err
+		}
+
+		row, err := csvRow(rv)
+		if err != nil {
+			return nil, err
+		}
+
+		return [][]string{header, row}, nil
+	default:
+		return nil, fmt.Errorf("-o csv: %T does not support csv output", v)
+	}
+}
+
+func csvHeader(rv reflect.Value) ([]string, error) {
+	rv = derefStruct(rv)
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("-o csv: %s does not support csv output", rv.Kind())
+	}
+
+	rt := rv.Type()
+	var header []string
+	for i := 0; i < rt.NumField(); i++ {
+		if f := rt.Field(i); f.PkgPath == "" { // exported fields only
+			header = append(header, csvFieldName(f))
+		}
+	}
+
+	return header, nil
+}
+
+func csvRow(rv reflect.Value) ([]string, error) {
+	rv = derefStruct(rv)
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("-o csv: %s does not support csv output", rv.Kind())
+	}
+
+	rt := rv.Type()
+	var row []string
+	for i := 0; i < rt.NumField(); i++ {
+		if rt.Field(i).PkgPath == "" {
+			row = append(row, fmt.Sprintf("%v", rv.Field(i).Interface()))
+		}
+	}
+
+	return row, nil
+}
+
+func derefStruct(rv reflect.Value) reflect.Value {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		rv = rv.Elem()
+	}
+
+	return rv
+}
+
+// csvFieldName picks a struct field's CSV header, preferring an explicit
+// `header:"..."` tag (the one tableprinter itself already honors),
+// falling back to the `json` tag name and finally the Go field name.
+func csvFieldName(f reflect.StructField) string {
+	if tag, ok := f.Tag.Lookup("header"); ok && tag != "" {
+		return tag
+	}
+
+	if tag, ok := f.Tag.Lookup("json"); ok {
+		if name := strings.Split(tag, ",")[0]; name != "" && name != "-" {
+			return name
+		}
+	}
+
+	return f.Name
+}
+
+// nameFormatter mirrors `kubectl get -o name`, it prints a single
+// `Stringer`-ish identifier per line and nothing else.
+func nameFormatter(out io.Writer, v interface{}, _ FormatOptions) error {
+	if s, ok := v.(fmt.Stringer); ok {
+		_, err := fmt.Fprintln(out, s.String())
+		return err
+	}
+
+	_, err := fmt.Fprintf(out, "%v\n", v)
+	return err
+}
+
+func goTemplateFormatter(out io.Writer, v interface{}, opts FormatOptions) error {
+	tmpl, err := template.New("bite-output").Parse(opts.Template)
+	if err != nil {
+		return err
+	}
+
+	return tmpl.Execute(out, v)
+}
+
+func goTemplateFileFormatter(out io.Writer, v interface{}, opts FormatOptions) error {
+	b, err := ioutil.ReadFile(opts.Template)
+	if err != nil {
+		return err
+	}
+
+	return goTemplateFormatter(out, v, FormatOptions{Template: string(b)})
+}
+
+// jsonPathFormatter evaluates opts.Template as an actual kubectl-flavored
+// JSONPath expression, i.e. `{.items[*].metadata.name}` or the brace-less
+// `.items[*].metadata.name`. This is deliberately not the same language as
+// `--query`'s JMESPath: the two look similar but parse differently, so
+// `-o jsonpath=...` would silently do the wrong thing if it just forwarded
+// into the JMESPath engine.
+func jsonPathFormatter(out io.Writer, v interface{}, opts FormatOptions) error {
+	// round-trip through JSON first so the expression walks the same
+	// field names `-o json` prints, not this process's Go struct field
+	// names.
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+
+	expr := strings.TrimSpace(opts.Template)
+	expr = strings.TrimPrefix(expr, "{")
+	expr = strings.TrimSuffix(expr, "}")
+	expr = strings.TrimPrefix(expr, ".")
+
+	results, err := jsonPathLookup([]interface{}{data}, strings.Split(expr, "."))
+	if err != nil {
+		return err
+	}
+
+	parts := make([]string, 0, len(results))
+	for _, r := range results {
+		parts = append(parts, fmt.Sprintf("%v", r))
+	}
+
+	_, err = fmt.Fprintln(out, strings.Join(parts, " "))
+	return err
+}
+
+// jsonPathLookup walks segments (i.e. ["items[*]", "metadata", "name"])
+// against every value currently in scope, fanning out on a `[*]` wildcard
+// and indexing into a `[n]`. It implements the common subset of JSONPath
+// bite's commands need rather than the full spec.
+func jsonPathLookup(values []interface{}, segments []string) ([]interface{}, error) {
+	for _, seg := range segments {
+		if seg == "" {
+			continue
+		}
+
+		field, index, hasIndex := splitJSONPathSegment(seg)
+
+		var next []interface{}
+		for _, v := range values {
+			if field != "" {
+				m, ok := v.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("-o jsonpath: %q is not an object", field)
+				}
+				v = m[field]
+			}
+
+			if !hasIndex {
+				next = append(next, v)
+				continue
+			}
+
+			list, ok := v.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("-o jsonpath: %q is not an array", seg)
+			}
+
+			if index == "*" {
+				next = append(next, list...)
+				continue
+			}
+
+			i, err := strconv.Atoi(index)
+			if err != nil || i < 0 || i >= len(list) {
+				return nil, fmt.Errorf("-o jsonpath: invalid index %q", seg)
+			}
+			next = append(next, list[i])
+		}
+
+		values = next
+	}
+
+	return values, nil
+}
+
+// splitJSONPathSegment splits "items[*]" into ("items", "*", true) and
+// "items" into ("items", "", false).
+func splitJSONPathSegment(seg string) (field, index string, hasIndex bool) {
+	start := strings.IndexByte(seg, '[')
+	end := strings.IndexByte(seg, ']')
+	if start == -1 || end == -1 || end < start {
+		return seg, "", false
+	}
+
+	return seg[:start], seg[start+1 : end], true
+}
+
+// RegisterOutputFormat makes fn available as `-o name` on this Application,
+// overriding a built-in formatter of the same name if one exists. Downstream
+// CLIs use this to add domain-specific writers, i.e. lenses-cli's
+// `-o describe`.
+func (app *Application) RegisterOutputFormat(name string, fn Formatter) {
+	if app.outputFormats == nil {
+		app.outputFormats = make(map[string]Formatter)
+	}
+
+	app.outputFormats[name] = fn
+}
+
+func (app *Application) lookupOutputFormat(name string) (Formatter, bool) {
+	if fn, ok := app.outputFormats[name]; ok {
+		return fn, true
+	}
+
+	fn, ok := builtinOutputFormats[name]
+	return fn, ok
+}
+
+// splitOutputFormat breaks a `-o` value such as `go-template={{.Name}}`
+// into its format name and the argument that followed `=`, if any.
+func splitOutputFormat(value string) (name string, arg string) {
+	if idx := strings.IndexByte(value, '='); idx != -1 {
+		return value[:idx], value[idx+1:]
+	}
+
+	return value, ""
+}
+
+func RegisterOutputFlagTo(set *pflag.FlagSet, ptr *string) {
+	if _, err := set.GetString(outputFlagKey); err != nil {
+		set.StringVarP(ptr, outputFlagKey, "o", defaultOutputFormat,
+			"-o, --output, output format, one of: table, json, yaml, csv, name, go-template=..., go-template-file=..., jsonpath=...")
+	}
+}
+
+func GetOutputFlagFrom(set *pflag.FlagSet) string {
+	s, _ := set.GetString(outputFlagKey)
+	if s == "" {
+		return defaultOutputFormat
+	}
+
+	return s
+}
+
+// GetOutputFlag returns the resolved `-o/--output` value of cmd,
+// `--machine-friendly` sugars to `json` when no explicit `-o` is given.
+func GetOutputFlag(cmd *cobra.Command) string {
+	if set := cmd.Flags(); set.Changed(outputFlagKey) {
+		return GetOutputFlagFrom(set)
+	}
+
+	if GetMachineFriendlyFlagFrom(cmd.Flags()) {
+		return "json"
+	}
+
+	return GetOutputFlagFrom(cmd.Flags())
+}
+
+// GetMachineFriendlyFlag reports true either when `--machine-friendly` was
+// given or when `-o/--output` was set to anything other than "table", it's
+// what PrintInfo uses to decide whether to suppress chatter. It does not
+// mean the resolved format is JSON specifically — use IsJSONOutput for that.
+func GetMachineFriendlyFlag(cmd *cobra.Command) bool {
+	if GetMachineFriendlyFlagFrom(cmd.Flags()) {
+		return true
+	}
+
+	name, _ := splitOutputFormat(GetOutputFlagFrom(cmd.Flags()))
+	return name != defaultOutputFormat
+}
+
+// IsJSONOutput reports whether cmd will actually render through the json
+// formatter, either via `--machine-friendly` or an explicit `-o json`.
+// Unlike GetMachineFriendlyFlag (true for any non-table format), this is
+// what error rendering needs: `-o yaml`/`-o csv`/`-o go-template=...` on a
+// failing command shouldn't get a raw `{"error":...}` blob in a format the
+// user didn't ask for.
+func IsJSONOutput(cmd *cobra.Command) bool {
+	name, _ := splitOutputFormat(GetOutputFlag(cmd))
+	return name == "json"
+}
+
+// PrintObject renders v to cmd's output writer using the format resolved
+// from `-o/--output` (or `--machine-friendly` as its json-producing alias).
+// tableOnlyFilters are only honored by the table formatter, exactly as
+// tableprinter.Print already behaves.
+func PrintObject(cmd *cobra.Command, v interface{}, tableOnlyFilters ...interface{}) error {
+	out := cmd.Root().OutOrStdout()
+	outputValue := GetOutputFlag(cmd)
+	name, arg := splitOutputFormat(outputValue)
+
+	app := Get(cmd)
+
+	var (
+		fn Formatter
+		ok bool
+	)
+	if app != nil {
+		fn, ok = app.lookupOutputFormat(name)
+	} else {
+		fn, ok = builtinOutputFormats[name]
+	}
+
+	if !ok {
+		return fmt.Errorf("-o %s: unknown output format", outputValue)
+	}
+
+	if name == "table" && len(tableOnlyFilters) > 0 {
+		tableprinter.Print(out, v, tableOnlyFilters...)
+		return nil
+	}
+
+	opts := FormatOptions{
+		Pretty:   !GetJSONNoPrettyFlag(cmd),
+		Query:    GetJSONQueryFlag(cmd),
+		Template: arg,
+	}
+
+	return fn(out, v, opts)
+}
@@ -0,0 +1,158 @@
+package bite
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chzyer/readline"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// EnableREPL makes the built binary start an interactive shell when it's
+// invoked without arguments (or with `shell`), instead of printing usage.
+// This turns a one-shot bite CLI into a persistent shell too, without every
+// downstream repo reinventing the read-eval-print loop.
+func (b *ApplicationBuilder) EnableREPL() *ApplicationBuilder {
+	b.app.repl = true
+	return b
+}
+
+// runREPL starts the interactive prompt: it tokenizes each line, routes it
+// to app.FindCommand and executes it against the same Application, so
+// app.Memory and any config/state it holds carries over between lines
+// exactly as it would across separate process invocations sharing a
+// config file.
+func runREPL(app *Application) error {
+	historyFile := ""
+	if home, err := os.UserHomeDir(); err == nil {
+		historyFile = filepath.Join(home, fmt.Sprintf(".%s_history", app.Name))
+	}
+
+	rl, err := readline.NewEx(&readline.Config{
+		Prompt:          fmt.Sprintf("%s> ", app.Name),
+		HistoryFile:     historyFile,
+		InterruptPrompt: "^C",
+		EOFPrompt:       "exit",
+	})
+	if err != nil {
+		return err
+	}
+	defer rl.Close()
+
+	for {
+		line, err := rl.Readline()
+		if err == readline.ErrInterrupt {
+			continue
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		switch line {
+		case "exit", "quit":
+			return nil
+		case "help":
+			app.CobraCommand.Usage()
+			continue
+		}
+
+		if err := runREPLLine(app, line); err != nil {
+			app.Print("%s\n", err.Error())
+		}
+	}
+}
+
+// runREPLLine tokenizes and executes a single REPL line against app's
+// command tree, the way a shell would split argv, honoring quoted
+// arguments so `-o go-template="{{.Name}}"` works the same as on the
+// command line.
+func runREPLLine(app *Application, line string) error {
+	args := splitREPLLine(line)
+	if len(args) == 0 {
+		return nil
+	}
+
+	if cmd, _ := app.FindCommand(args); cmd == nil {
+		return fmt.Errorf("%s: command not found", args[0])
+	}
+
+	// pflag.Parse only overwrites flags explicitly present in args, it never
+	// resets the ones it doesn't see back to their default, so without this
+	// every flag (-o, --machine-friendly, --silent, ...) set on one line
+	// would stay "sticky" on every line after it.
+	resetFlags(app.CobraCommand)
+
+	// Command.ExecuteC redirects to c.Root().ExecuteC() whenever the found
+	// command has a parent, so args must be set and executed from the root,
+	// not from the subcommand FindCommand located.
+	app.CobraCommand.SetArgs(args)
+	return app.CobraCommand.Execute()
+}
+
+// resetFlags walks cmd and every descendant, resetting each flag back to
+// its default value and clearing Changed, it's what undoes pflag's
+// "only touch what Parse saw" behavior between REPL lines.
+func resetFlags(cmd *cobra.Command) {
+	resetFlagSet(cmd.Flags())
+	resetFlagSet(cmd.PersistentFlags())
+
+	for _, c := range cmd.Commands() {
+		resetFlags(c)
+	}
+}
+
+func resetFlagSet(fs *pflag.FlagSet) {
+	fs.VisitAll(func(f *pflag.Flag) {
+		_ = f.Value.Set(f.DefValue)
+		f.Changed = false
+	})
+}
+
+// splitREPLLine is a small shell-like tokenizer supporting single and
+// double quoted arguments, i.e. `topics create "my topic" -o json`.
+func splitREPLLine(line string) []string {
+	var (
+		args  []string
+		cur   strings.Builder
+		quote rune
+	)
+
+	flush := func() {
+		if cur.Len() > 0 {
+			args = append(args, cur.String())
+			cur.Reset()
+		}
+	}
+
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == ' ' || r == '\t':
+			flush()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	flush()
+
+	return args
+}